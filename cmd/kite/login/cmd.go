@@ -17,23 +17,41 @@ limitations under the License.
 package login
 
 import (
-	"bufio"
+	"context"
 	"errors"
 	"fmt"
 	"os"
-	"strings"
+	"time"
 
 	"github.com/PagerDuty/go-pagerduty"
 	"github.com/openshift/pagerduty-short-circuiter/cmd/kite/teams"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/ansi"
 	"github.com/openshift/pagerduty-short-circuiter/pkg/client"
 	"github.com/openshift/pagerduty-short-circuiter/pkg/config"
 	"github.com/openshift/pagerduty-short-circuiter/pkg/constants"
+	githubauth "github.com/openshift/pagerduty-short-circuiter/pkg/github/auth"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/oauth"
 	"github.com/spf13/cobra"
 )
 
+// pagerDutyOAuth describes the PagerDuty OAuth application kite logs a user
+// in as through the browser authorization-code-with-PKCE flow.
+var pagerDutyOAuth = &oauth.Config{
+	ClientID: "kite-cli",
+	Scopes:   []string{"read", "write"},
+}
+
+// githubOAuth describes the GitHub OAuth application kite logs a user in as
+// through the device flow, to fetch SOPs out of GitHub on their behalf.
+var githubOAuth = &githubauth.Config{
+	ClientID: "kite-cli",
+	Scopes:   []string{"repo", "read:org"},
+}
+
 var loginArgs struct {
-	apiKey      string
-	accessToken string
+	apiKey        string
+	accessToken   string
+	refreshGitHub bool
 }
 
 var Cmd = &cobra.Command{
@@ -59,6 +77,12 @@ func init() {
 		"",
 		"GitHub Personal Access Token generated from "+constants.AccessTokenURL+"\nUse this option to overwrite the existing Access Token.",
 	)
+	Cmd.Flags().BoolVar(
+		&loginArgs.refreshGitHub,
+		"refresh-github",
+		false,
+		"Re-run the GitHub device flow login even if a GitHub access token is already on file.",
+	)
 }
 
 // loginHandler handles the login flow into kite.
@@ -78,10 +102,19 @@ func loginHandler(cmd *cobra.Command, args []string) error {
 		cfg = new(config.Config)
 	}
 
-	// If the key arg is not empty
+	// A config file written before kite stored secrets in the OS keychain
+	// still carries them in plaintext; the Save calls below migrate them
+	// into the keystore and strip them from the file.
+	if cfg.InsecureConfigDetected {
+		fmt.Println("Found credentials stored in plaintext, moving them into your OS keychain...")
+	}
+
+	// If the --api-key flag is set, it overrides any existing credential and
+	// is used as-is. This is the supported path for CI and service accounts
+	// that cannot complete an interactive browser login.
 	if loginArgs.apiKey != "" {
 
-		cfg.ApiKey = loginArgs.apiKey
+		cfg.ApiKey = *config.NewAPIKeyFromString(loginArgs.apiKey)
 
 		// Save the key in the config file
 		err = config.Save(cfg)
@@ -91,22 +124,42 @@ func loginHandler(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	// API key is not found in the config file
-	if len(cfg.ApiKey) == 0 {
+	// Neither an API key nor a valid OAuth session is on file: drive the
+	// user through the browser login flow.
+	if cfg.ApiKey.IsEmpty() && (cfg.OAuthAccessToken == "" || cfg.OAuthRefreshToken == "") {
+
+		err = browserLogin(cfg)
+
+		if err != nil {
+			return err
+		}
 
-		// Create a new API key and store it in the config file
-		err = generateNewKey(cfg)
+		// Save the tokens in the config file
+		err = config.Save(cfg)
 
 		if err != nil {
 			return err
 		}
 	}
 
-	// API key is not found in the config file
-	if len(cfg.AccessToken) == 0 {
+	// If the --access-token flag is set, it overrides any existing GitHub
+	// access token and is used as-is; its scopes are unknown to kite, so it
+	// is trusted rather than immediately re-checked below.
+	if loginArgs.accessToken != "" {
+		cfg.AccessToken = loginArgs.accessToken
+		cfg.AccessTokenScopes = nil
+	}
+
+	// No GitHub access token is on file, --refresh-github was passed, or the
+	// token on file has known scopes missing "repo": run the GitHub device
+	// flow to obtain (or replace) one. A pasted token's scopes are unknown
+	// (nil) rather than insufficient, so it is trusted instead of forcing a
+	// re-login.
+	needsGitHubLogin := cfg.AccessToken == "" || loginArgs.refreshGitHub || (cfg.AccessTokenScopes != nil && !githubauth.HasScope(cfg.AccessTokenScopes, "repo"))
 
-		// Create a new API key and store it in the config file
-		err = generateNewAccessToken(cfg)
+	if needsGitHubLogin {
+
+		err = githubDeviceLogin(cfg)
 
 		if err != nil {
 			return err
@@ -127,8 +180,9 @@ func loginHandler(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	// Login using the API key in the configuration file
-	user, err = Login(cfg.ApiKey, pdClient)
+	// Login using the credential (API key or OAuth access token) in the
+	// configuration file
+	user, err = Login(client.Credential(cfg), pdClient)
 
 	if err != nil {
 		return err
@@ -137,6 +191,21 @@ func loginHandler(cmd *cobra.Command, args []string) error {
 	// Print login success message
 	successMessage(user)
 
+	// Look up and warn about an API key nearing its expiration. OAuth
+	// sessions already surface their expiry through TokenExpiry and are
+	// refreshed automatically, so this only applies to the --api-key path.
+	if !cfg.ApiKey.IsEmpty() {
+		warnAPIKeyExpiration(cfg)
+
+		// Persist any expiration/scope metadata and warning timestamp
+		// populated above
+		err = config.Save(cfg)
+
+		if err != nil {
+			return err
+		}
+	}
+
 	// Check if user has selected a team
 	if cfg.TeamID == "" {
 		teamdID, name, err := teams.SelectTeam(pdClient, os.Stdin)
@@ -159,42 +228,72 @@ func loginHandler(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-// generateNewKey prompts the user to create a new API key and saves it to the config file.
-func generateNewKey(cfg *config.Config) (err error) {
-	//prompts the user to generate an API Key
-	fmt.Println("In order to login it is mandatory to provide an API key.\nThe recommended way is to generate an API key via: " + constants.APIKeyURL)
-
-	//Takes standard input from the user and stores it in a variable
-	reader := bufio.NewReader(os.Stdin)
+// browserLogin drives the user through PagerDuty's OAuth 2.0
+// authorization-code-with-PKCE flow and stores the resulting tokens on cfg.
+func browserLogin(cfg *config.Config) error {
+	fmt.Println("Opening your browser to log in to PagerDuty...")
 
-	fmt.Print("API Key: ")
-
-	cfg.ApiKey, err = reader.ReadString('\n')
+	token, err := pagerDutyOAuth.Login(context.Background())
 
 	if err != nil {
-		return err
+		return fmt.Errorf("browser login failed: %w", err)
 	}
 
+	cfg.OAuthAccessToken = token.AccessToken
+	cfg.OAuthRefreshToken = token.RefreshToken
+	cfg.TokenType = token.TokenType
+	cfg.TokenExpiry = token.Expiry
+
 	return nil
 }
 
-// generateNewKey prompts the user to create a new API key and saves it to the config file.
-func generateNewAccessToken(cfg *config.Config) (err error) {
-	//prompts the user to generate an API Key
-	fmt.Println("\nIn order to view SOP it is mandatory to provide an GitHub Access Token.\nThe recommended way is to generate a token via: " + constants.AccessTokenURL)
+// apiKeyWarnInterval is the minimum time between two expiration warnings
+// for the same API key.
+const apiKeyWarnInterval = 12 * time.Hour
 
-	//Takes standard input from the user and stores it in a variable
-	reader := bufio.NewReader(os.Stdin)
+// warnAPIKeyExpiration looks up cfg.ApiKey's expiration and scope via
+// PagerDuty's token info endpoint and, if it is nearing expiration, prints
+// a warning no more than once every apiKeyWarnInterval.
+func warnAPIKeyExpiration(cfg *config.Config) {
+	info, err := client.GetTokenInfo(cfg.ApiKey.Key)
 
-	fmt.Print("GitHub Access Token: ")
+	if err != nil {
+		// Token info isn't available for every key type, e.g. classic
+		// keys, so failing to look it up is not fatal to login.
+		return
+	}
 
-	cfg.AccessToken, err = reader.ReadString('\n')
-	cfg.AccessToken = strings.TrimSuffix(cfg.AccessToken, "\n")
+	cfg.ApiKey.Expiration = info.ExpiresAt
+	cfg.ApiKey.Scope = info.Scope
+
+	if time.Since(cfg.APIKeyWarnedAt) < apiKeyWarnInterval {
+		return
+	}
+
+	switch {
+	case cfg.ApiKey.ExpiresWithin(24 * time.Hour):
+		ansi.Danger(fmt.Sprintf("Your PagerDuty API key expires at %s. Run `kite login --api-key` to replace it.", cfg.ApiKey.Expiration.Format(time.RFC1123)))
+		cfg.APIKeyWarnedAt = time.Now()
+	case cfg.ApiKey.ExpiresWithin(14 * 24 * time.Hour):
+		ansi.Warn(fmt.Sprintf("Your PagerDuty API key expires at %s. Run `kite login --api-key` to replace it.", cfg.ApiKey.Expiration.Format(time.RFC1123)))
+		cfg.APIKeyWarnedAt = time.Now()
+	}
+}
+
+// githubDeviceLogin drives the user through GitHub's OAuth 2.0 device flow
+// and stores the resulting access token and scopes on cfg.
+func githubDeviceLogin(cfg *config.Config) error {
+	fmt.Println("\nA GitHub access token is needed to fetch SOPs.")
+
+	token, err := githubOAuth.Login()
 
 	if err != nil {
-		return err
+		return fmt.Errorf("github login failed: %w", err)
 	}
 
+	cfg.AccessToken = token.AccessToken
+	cfg.AccessTokenScopes = token.Scopes
+
 	return nil
 }
 
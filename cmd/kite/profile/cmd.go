@@ -0,0 +1,106 @@
+/*
+Copyright © 2021 Red Hat, Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package profile implements the `kite profile` subcommands used to manage
+// named sets of PagerDuty credentials.
+package profile
+
+import (
+	"fmt"
+
+	"github.com/openshift/pagerduty-short-circuiter/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+// Cmd is the parent `kite profile` command.
+var Cmd = &cobra.Command{
+	Use:   "profile",
+	Short: "Manage kite profiles",
+	Long:  `The kite profile command lists, switches between and deletes the named PagerDuty profiles stored in the kite configuration file.`,
+}
+
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the available profiles",
+	Args:  cobra.NoArgs,
+	RunE:  listHandler,
+}
+
+var useCmd = &cobra.Command{
+	Use:   "use <name>",
+	Short: "Set the default profile",
+	Args:  cobra.ExactArgs(1),
+	RunE:  useHandler,
+}
+
+var deleteCmd = &cobra.Command{
+	Use:   "delete <name>",
+	Short: "Delete a profile",
+	Args:  cobra.ExactArgs(1),
+	RunE:  deleteHandler,
+}
+
+func init() {
+	Cmd.AddCommand(listCmd)
+	Cmd.AddCommand(useCmd)
+	Cmd.AddCommand(deleteCmd)
+}
+
+// listHandler prints every profile on disk.
+func listHandler(cmd *cobra.Command, args []string) error {
+	names, err := config.ListProfiles()
+
+	if err != nil {
+		return err
+	}
+
+	if len(names) == 0 {
+		fmt.Println("No profiles found, run `kite login` to create one.")
+		return nil
+	}
+
+	for _, name := range names {
+		fmt.Println(name)
+	}
+
+	return nil
+}
+
+// useHandler sets the default profile to args[0].
+func useHandler(cmd *cobra.Command, args []string) error {
+	name := args[0]
+
+	if err := config.SetCurrentProfile(name); err != nil {
+		return err
+	}
+
+	fmt.Printf("Now using profile: %s\n", name)
+
+	return nil
+}
+
+// deleteHandler deletes the profile named args[0].
+func deleteHandler(cmd *cobra.Command, args []string) error {
+	name := args[0]
+
+	if err := config.DeleteProfile(name); err != nil {
+		return err
+	}
+
+	fmt.Printf("Deleted profile: %s\n", name)
+
+	return nil
+}
@@ -0,0 +1,57 @@
+/*
+Copyright © 2021 Red Hat, Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"github.com/openshift/pagerduty-short-circuiter/cmd/kite/login"
+	"github.com/openshift/pagerduty-short-circuiter/cmd/kite/profile"
+	"github.com/openshift/pagerduty-short-circuiter/cmd/kite/tokens"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+var rootArgs struct {
+	profile string
+}
+
+var rootCmd = &cobra.Command{
+	Use:   "kite",
+	Short: "kite is a PagerDuty CLI for OpenShift on-call engineers",
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		if rootArgs.profile != "" {
+			config.UseProfile(rootArgs.profile)
+		}
+	},
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(
+		&rootArgs.profile,
+		"profile",
+		"",
+		"PagerDuty profile to use, overrides $PDCLI_PROFILE and the saved default profile.",
+	)
+
+	rootCmd.AddCommand(login.Cmd)
+	rootCmd.AddCommand(profile.Cmd)
+	rootCmd.AddCommand(tokens.Cmd)
+}
+
+// Execute runs the kite root command.
+func Execute() error {
+	return rootCmd.Execute()
+}
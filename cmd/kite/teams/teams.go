@@ -0,0 +1,77 @@
+/*
+Copyright © 2021 Red Hat, Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package teams
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/PagerDuty/go-pagerduty"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/client"
+)
+
+// SelectTeam lists the PagerDuty teams returned by ListTeams and prompts
+// the user to choose one, returning its ID and name. The list is not
+// filtered to the teams the current user is a member of.
+func SelectTeam(pdClient client.PagerDutyClient, input io.Reader) (id string, name string, err error) {
+	resp, err := pdClient.ListTeams(pagerduty.ListTeamOptions{})
+
+	if err != nil {
+		return "", "", err
+	}
+
+	if len(resp.Teams) == 0 {
+		return "", "", fmt.Errorf("no teams found")
+	}
+
+	fmt.Println("Select a team:")
+
+	for i, team := range resp.Teams {
+		fmt.Printf("[%d] %s\n", i+1, team.Name)
+	}
+
+	reader := bufio.NewReader(input)
+
+	fmt.Print("Team: ")
+
+	line, err := reader.ReadString('\n')
+
+	if err != nil {
+		return "", "", err
+	}
+
+	choice, err := strconv.Atoi(trimNewline(line))
+
+	if err != nil || choice < 1 || choice > len(resp.Teams) {
+		return "", "", fmt.Errorf("invalid team selection")
+	}
+
+	team := resp.Teams[choice-1]
+
+	return team.ID, team.Name, nil
+}
+
+// trimNewline strips a trailing newline (and carriage return) from s.
+func trimNewline(s string) string {
+	for len(s) > 0 && (s[len(s)-1] == '\n' || s[len(s)-1] == '\r') {
+		s = s[:len(s)-1]
+	}
+
+	return s
+}
@@ -0,0 +1,214 @@
+/*
+Copyright © 2021 Red Hat, Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package tokens implements the `kite tokens` subcommands used to
+// provision and revoke PagerDuty personal access tokens on behalf of the
+// logged in user, for use by automation.
+package tokens
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/PagerDuty/go-pagerduty"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/client"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/config"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/constants"
+	"github.com/spf13/cobra"
+)
+
+var createArgs struct {
+	name     string
+	scope    string
+	lifetime time.Duration
+}
+
+// Cmd is the parent `kite tokens` command.
+var Cmd = &cobra.Command{
+	Use:   "tokens",
+	Short: "Manage PagerDuty personal access tokens",
+	Long:  `The kite tokens command provisions, lists and revokes PagerDuty personal access tokens for use by automation, without having to leave the CLI.`,
+}
+
+var createCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create a new personal access token",
+	Args:  cobra.NoArgs,
+	RunE:  createHandler,
+}
+
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List personal access tokens created through kite",
+	Args:  cobra.NoArgs,
+	RunE:  listHandler,
+}
+
+var revokeCmd = &cobra.Command{
+	Use:   "revoke <id>",
+	Short: "Revoke a personal access token",
+	Args:  cobra.ExactArgs(1),
+	RunE:  revokeHandler,
+}
+
+func init() {
+	createCmd.Flags().StringVar(&createArgs.name, "name", "", "Name to give the new token, e.g. \"ci-bot\".")
+	createCmd.Flags().StringVar(&createArgs.scope, "scope", "read", "Access level to record for the new token: \"read\" or \"write\".")
+	createCmd.Flags().DurationVar(&createArgs.lifetime, "lifetime", 0, "How long the token should be considered valid, e.g. \"720h\". Defaults to no expiry.")
+	createCmd.MarkFlagRequired("name")
+
+	Cmd.AddCommand(createCmd)
+	Cmd.AddCommand(listCmd)
+	Cmd.AddCommand(revokeCmd)
+}
+
+// createHandler provisions a new PagerDuty personal access token and
+// records its metadata in the kite configuration file.
+func createHandler(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+
+	if err != nil {
+		return err
+	}
+
+	pdClient, err := client.NewClient().Connect()
+
+	if err != nil {
+		return err
+	}
+
+	// Connect may have refreshed and persisted a new OAuth access token
+	// under the hood; reload cfg so the credential used below matches what
+	// pdClient itself just authenticated with.
+	cfg, err = config.Load()
+
+	if err != nil {
+		return err
+	}
+
+	user, err := pdClient.GetCurrentUser(pagerduty.GetCurrentUserOptions{})
+
+	if err != nil {
+		return err
+	}
+
+	token, err := client.CreateUserToken(client.AuthHeader(cfg), user.ID, createArgs.name)
+
+	if err != nil {
+		fmt.Printf("Could not create a token via the API (%v).\nGenerate one manually instead via: %s\n", err, constants.UserTokenURL)
+		return err
+	}
+
+	meta := config.TokenMeta{
+		ID:        token.ID,
+		Name:      createArgs.name,
+		Scope:     createArgs.scope,
+		CreatedAt: time.Now(),
+	}
+
+	if createArgs.lifetime > 0 {
+		meta.Expiry = meta.CreatedAt.Add(createArgs.lifetime)
+	}
+
+	cfg.Tokens = append(cfg.Tokens, meta)
+
+	if err := config.Save(cfg); err != nil {
+		return err
+	}
+
+	fmt.Printf("Created token %q (id: %s). This secret is shown only once:\n%s\n", createArgs.name, token.ID, token.Token)
+
+	return nil
+}
+
+// listHandler prints every token kite has provisioned for the current
+// profile.
+func listHandler(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+
+	if err != nil {
+		return err
+	}
+
+	if len(cfg.Tokens) == 0 {
+		fmt.Println("No tokens found, run `kite tokens create --name <name>` to provision one.")
+		return nil
+	}
+
+	for _, t := range cfg.Tokens {
+		fmt.Printf("%s\t%s\tscope=%s\tcreated=%s", t.ID, t.Name, t.Scope, t.CreatedAt.Format(time.RFC3339))
+
+		if !t.Expiry.IsZero() {
+			fmt.Printf("\texpires=%s", t.Expiry.Format(time.RFC3339))
+		}
+
+		fmt.Println()
+	}
+
+	return nil
+}
+
+// revokeHandler revokes the token identified by args[0] and removes it
+// from the kite configuration file.
+func revokeHandler(cmd *cobra.Command, args []string) error {
+	id := args[0]
+
+	cfg, err := config.Load()
+
+	if err != nil {
+		return err
+	}
+
+	pdClient, err := client.NewClient().Connect()
+
+	if err != nil {
+		return err
+	}
+
+	// Connect may have refreshed and persisted a new OAuth access token
+	// under the hood; reload cfg so the credential used below matches what
+	// pdClient itself just authenticated with.
+	cfg, err = config.Load()
+
+	if err != nil {
+		return err
+	}
+
+	user, err := pdClient.GetCurrentUser(pagerduty.GetCurrentUserOptions{})
+
+	if err != nil {
+		return err
+	}
+
+	if err := client.RevokeUserToken(client.AuthHeader(cfg), user.ID, id); err != nil {
+		return err
+	}
+
+	for i, t := range cfg.Tokens {
+		if t.ID == id {
+			cfg.Tokens = append(cfg.Tokens[:i], cfg.Tokens[i+1:]...)
+			break
+		}
+	}
+
+	if err := config.Save(cfg); err != nil {
+		return err
+	}
+
+	fmt.Printf("Revoked token: %s\n", id)
+
+	return nil
+}
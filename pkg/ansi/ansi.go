@@ -0,0 +1,52 @@
+/*
+Copyright © 2021 Red Hat, Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package ansi provides minimal helpers for printing colored terminal
+// output, used to draw the user's attention to warnings.
+package ansi
+
+import (
+	"fmt"
+	"os"
+)
+
+const (
+	yellow = "\033[33m"
+	red    = "\033[31m"
+	reset  = "\033[0m"
+)
+
+// Yellow wraps s in the ANSI escape codes for yellow text.
+func Yellow(s string) string {
+	return yellow + s + reset
+}
+
+// Red wraps s in the ANSI escape codes for red text.
+func Red(s string) string {
+	return red + s + reset
+}
+
+// Warn prints s to stderr in yellow, for warnings that don't yet require
+// the user's immediate attention.
+func Warn(s string) {
+	fmt.Fprintln(os.Stderr, Yellow(s))
+}
+
+// Danger prints s to stderr in red, for warnings that require the user's
+// immediate attention.
+func Danger(s string) {
+	fmt.Fprintln(os.Stderr, Red(s))
+}
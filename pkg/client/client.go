@@ -0,0 +1,106 @@
+/*
+Copyright © 2021 Red Hat, Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package client wraps the go-pagerduty client so that the rest of kite
+// only ever depends on the narrow PagerDutyClient interface below.
+package client
+
+import (
+	"time"
+
+	"github.com/PagerDuty/go-pagerduty"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/config"
+	"github.com/openshift/pagerduty-short-circuiter/pkg/oauth"
+)
+
+// refreshSkew is how far ahead of the recorded expiry kite proactively
+// refreshes an OAuth access token, to avoid racing a request against it.
+const refreshSkew = 60 * time.Second
+
+// oauthConfig describes the PagerDuty OAuth application kite authenticates
+// as when refreshing a token obtained via the browser login flow.
+var oauthConfig = &oauth.Config{
+	ClientID: "kite-cli",
+	Scopes:   []string{"read", "write"},
+}
+
+// PagerDutyClient is the subset of the go-pagerduty client used by kite.
+// It exists so that API calls can be mocked out in tests.
+type PagerDutyClient interface {
+	GetCurrentUser(o pagerduty.GetCurrentUserOptions) (*pagerduty.User, error)
+	ListOnCallUsers(scheduleID string, o pagerduty.ListOnCallUsersOptions) ([]pagerduty.User, error)
+	ListTeams(o pagerduty.ListTeamOptions) (*pagerduty.ListTeamResponse, error)
+}
+
+// Client is the default implementation of kite's PagerDutyClient.
+type Client struct {
+	apiKey string
+}
+
+// NewClient returns a new, unconnected Client.
+func NewClient() *Client {
+	return &Client{}
+}
+
+// Connect loads the kite configuration, resolves the credential to
+// authenticate with and returns a go-pagerduty client. If the configuration
+// holds an OAuth token that has expired, or is about to, it is transparently
+// refreshed and the refreshed token is persisted before connecting.
+func (c *Client) Connect() (PagerDutyClient, error) {
+	cfg, err := config.Load()
+
+	if err != nil {
+		return nil, err
+	}
+
+	// A manually provided API key always takes precedence, matching the
+	// --api-key fallback used for CI and service accounts.
+	if !cfg.ApiKey.IsEmpty() {
+		c.apiKey = cfg.ApiKey.Key
+
+		return pagerduty.NewClient(c.apiKey), nil
+	}
+
+	if cfg.OAuthRefreshToken != "" && time.Now().After(cfg.TokenExpiry.Add(-refreshSkew)) {
+		token, err := oauthConfig.Refresh(cfg.OAuthRefreshToken)
+
+		if err != nil {
+			return nil, err
+		}
+
+		cfg.OAuthAccessToken = token.AccessToken
+
+		// The refresh grant is not required to return a new refresh token
+		// (RFC 6749 §6); keep reusing the existing one when it doesn't.
+		if token.RefreshToken != "" {
+			cfg.OAuthRefreshToken = token.RefreshToken
+		}
+
+		cfg.TokenType = token.TokenType
+		cfg.TokenExpiry = token.Expiry
+
+		if err := config.Save(cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	// OAuth access tokens authenticate with "Authorization: Bearer", not
+	// the "Authorization: Token token=" scheme NewClient uses for API
+	// keys, so they need go-pagerduty's OAuth-aware constructor.
+	c.apiKey = cfg.OAuthAccessToken
+
+	return pagerduty.NewOAuthClient(c.apiKey), nil
+}
@@ -0,0 +1,42 @@
+/*
+Copyright © 2021 Red Hat, Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import "github.com/openshift/pagerduty-short-circuiter/pkg/config"
+
+// Credential returns the PagerDuty credential cfg should authenticate API
+// calls with, preferring a manually provided API key over an OAuth access
+// token.
+func Credential(cfg *config.Config) string {
+	if !cfg.ApiKey.IsEmpty() {
+		return cfg.ApiKey.Key
+	}
+
+	return cfg.OAuthAccessToken
+}
+
+// AuthHeader returns the HTTP Authorization header value cfg's credential
+// should be sent with: the "Token token=" scheme for a manually provided
+// API key, or "Bearer" for an OAuth access token, matching the split
+// between go-pagerduty's NewClient and NewOAuthClient (see client.go).
+func AuthHeader(cfg *config.Config) string {
+	if !cfg.ApiKey.IsEmpty() {
+		return "Token token=" + cfg.ApiKey.Key
+	}
+
+	return "Bearer " + cfg.OAuthAccessToken
+}
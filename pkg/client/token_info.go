@@ -0,0 +1,70 @@
+/*
+Copyright © 2021 Red Hat, Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// tokenInfoURL is PagerDuty's endpoint for inspecting the metadata of the
+// credential currently in use, namely its scope and expiration.
+const tokenInfoURL = "https://api.pagerduty.com/oauth/token_info"
+
+// TokenInfo describes the scope and expiration of a PagerDuty credential.
+type TokenInfo struct {
+	Scope     string    `json:"scope"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// GetTokenInfo looks up the scope and expiration of the given PagerDuty API
+// key, sent with the "Token token=" scheme PagerDuty's REST API expects for
+// API keys (see client.go's NewClient/NewOAuthClient split). Any status
+// other than 200 OK is returned as an error; a response without an
+// expires_at field leaves ExpiresAt as the zero time.
+func GetTokenInfo(apiKey string) (*TokenInfo, error) {
+	req, err := http.NewRequest(http.MethodGet, tokenInfoURL, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Authorization", "Token token="+apiKey)
+	req.Header.Set("Accept", "application/vnd.pagerduty+json;version=2")
+
+	resp, err := http.DefaultClient.Do(req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("token info request failed: %s", resp.Status)
+	}
+
+	info := new(TokenInfo)
+
+	if err := json.NewDecoder(resp.Body).Decode(info); err != nil {
+		return nil, err
+	}
+
+	return info, nil
+}
@@ -0,0 +1,107 @@
+/*
+Copyright © 2021 Red Hat, Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// userTokensURL is PagerDuty's REST API endpoint for managing a user's
+// personal access tokens.
+const userTokensURL = "https://api.pagerduty.com/users/%s/user_tokens"
+
+// UserToken is a PagerDuty personal access token. Token is only populated
+// by CreateUserToken and is never returned by later lookups.
+type UserToken struct {
+	ID       string `json:"id"`
+	Name     string `json:"description"`
+	Token    string `json:"token,omitempty"`
+	LastUsed string `json:"last_used,omitempty"`
+}
+
+// CreateUserToken asks PagerDuty to provision a new personal access token
+// for userID, returning its id and one-time secret. authHeader is the full
+// Authorization header value to authenticate with, as returned by
+// client.AuthHeader.
+func CreateUserToken(authHeader, userID, name string) (*UserToken, error) {
+	body, err := json.Marshal(map[string]interface{}{
+		"user_token": map[string]string{"description": name},
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf(userTokensURL, userID), bytes.NewReader(body))
+
+	if err != nil {
+		return nil, err
+	}
+
+	wrapper := new(struct {
+		UserToken UserToken `json:"user_token"`
+	})
+
+	if err := doUserTokenRequest(authHeader, req, http.StatusCreated, wrapper); err != nil {
+		return nil, err
+	}
+
+	return &wrapper.UserToken, nil
+}
+
+// RevokeUserToken invalidates the personal access token identified by
+// tokenID. authHeader is the full Authorization header value to
+// authenticate with, as returned by client.AuthHeader.
+func RevokeUserToken(authHeader, userID, tokenID string) error {
+	req, err := http.NewRequest(http.MethodDelete, fmt.Sprintf(userTokensURL, userID)+"/"+tokenID, nil)
+
+	if err != nil {
+		return err
+	}
+
+	return doUserTokenRequest(authHeader, req, http.StatusNoContent, nil)
+}
+
+// doUserTokenRequest sets the headers common to every user token API call,
+// executes req, checks its status code and, if out is non-nil, decodes the
+// response body into it.
+func doUserTokenRequest(authHeader string, req *http.Request, wantStatus int, out interface{}) error {
+	req.Header.Set("Authorization", authHeader)
+	req.Header.Set("Accept", "application/vnd.pagerduty+json;version=2")
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != wantStatus {
+		return fmt.Errorf("user token request failed: %s", resp.Status)
+	}
+
+	if out == nil {
+		return nil
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
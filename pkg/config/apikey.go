@@ -0,0 +1,102 @@
+/*
+Copyright © 2021 Red Hat, Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"strings"
+	"time"
+)
+
+// PagerDuty API keys carry a scope that can be inferred from their prefix.
+const (
+	// ScopeUser identifies a key scoped to a single user (e.g. "u+...").
+	ScopeUser = "user"
+
+	// ScopeAccount identifies an account-level, full-access key (e.g. "y_...").
+	ScopeAccount = "account"
+
+	// ScopeLegacy identifies a classic, unprefixed API key. Its scope
+	// cannot be determined from the key alone.
+	ScopeLegacy = "legacy"
+)
+
+// APIKey is a PagerDuty API key together with the metadata kite needs to
+// warn users before it expires.
+type APIKey struct {
+	// Key is the raw API key/token string used to authenticate requests.
+	Key string `json:"key,omitempty"`
+
+	// Expiration is when the key stops being valid. It is the zero value
+	// when the key's expiration is unknown, e.g. for classic keys that
+	// never expire.
+	Expiration time.Time `json:"expiration,omitempty"`
+
+	// Scope describes what the key is allowed to access, see the Scope*
+	// constants above.
+	Scope string `json:"scope,omitempty"`
+
+	// Profile is the name of the profile this key belongs to.
+	Profile string `json:"-"`
+}
+
+// NewAPIKey returns an APIKey with an explicit expiration and scope.
+func NewAPIKey(key string, expiration time.Time, scope string) *APIKey {
+	return &APIKey{
+		Key:        key,
+		Expiration: expiration,
+		Scope:      scope,
+	}
+}
+
+// NewAPIKeyFromString returns an APIKey with its scope inferred from the
+// key's prefix. The expiration is left unset; callers that can reach the
+// PagerDuty API should populate it separately.
+func NewAPIKeyFromString(key string) *APIKey {
+	return &APIKey{
+		Key:   key,
+		Scope: inferScope(key),
+	}
+}
+
+// inferScope guesses a key's scope from its prefix, falling back to
+// ScopeLegacy for keys that predate PagerDuty's prefixed key formats.
+func inferScope(key string) string {
+	switch {
+	case strings.HasPrefix(key, "u+"):
+		return ScopeUser
+	case strings.HasPrefix(key, "y_"):
+		return ScopeAccount
+	default:
+		return ScopeLegacy
+	}
+}
+
+// IsEmpty reports whether the APIKey holds no key string, e.g. because it
+// is the zero value.
+func (k APIKey) IsEmpty() bool {
+	return k.Key == ""
+}
+
+// ExpiresWithin reports whether the key has a known expiration that falls
+// within d of now. It is always false for keys with no known expiration.
+func (k APIKey) ExpiresWithin(d time.Duration) bool {
+	if k.Expiration.IsZero() {
+		return false
+	}
+
+	return time.Now().Add(d).After(k.Expiration)
+}
@@ -0,0 +1,333 @@
+/*
+Copyright © 2021 Red Hat, Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package config handles loading, saving and locating the kite
+// configuration file that is persisted to the user's home directory.
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/openshift/pagerduty-short-circuiter/pkg/constants"
+)
+
+// Config represents the contents of the kite configuration file.
+type Config struct {
+	// ApiKey is the PagerDuty API key used to authenticate REST API calls.
+	// It remains supported as a fallback for CI and service accounts that
+	// cannot complete an interactive browser login.
+	ApiKey APIKey `json:"apikey,omitempty"`
+
+	// APIKeyWarnedAt is the last time the user was warned about ApiKey
+	// nearing its expiration. It throttles expiration warnings to at most
+	// once every 12 hours.
+	APIKeyWarnedAt time.Time `json:"apikey_warned_at,omitempty"`
+
+	// AccessToken is the GitHub access token used to fetch SOPs, obtained
+	// either through the GitHub device flow or pasted via --access-token.
+	AccessToken string `json:"accesstoken,omitempty"`
+
+	// AccessTokenScopes records the OAuth scopes AccessToken was granted,
+	// so that SOP fetch code can pre-check that "repo" is present and only
+	// re-prompt the user when scopes are insufficient.
+	AccessTokenScopes []string `json:"access_token_scopes,omitempty"`
+
+	// OAuthAccessToken is the PagerDuty OAuth access token obtained through
+	// the browser login flow.
+	OAuthAccessToken string `json:"oauth_access_token,omitempty"`
+
+	// OAuthRefreshToken is used to silently obtain a new OAuthAccessToken
+	// once it expires.
+	OAuthRefreshToken string `json:"oauth_refresh_token,omitempty"`
+
+	// TokenType is the OAuth token type returned alongside OAuthAccessToken,
+	// e.g. "bearer".
+	TokenType string `json:"token_type,omitempty"`
+
+	// TokenExpiry is the absolute time at which OAuthAccessToken expires.
+	TokenExpiry time.Time `json:"token_expiry,omitempty"`
+
+	// TeamID is the unique identifier of the user's selected PagerDuty team.
+	TeamID string `json:"teamid,omitempty"`
+
+	// Team is the human readable name of the user's selected PagerDuty team.
+	Team string `json:"team,omitempty"`
+
+	// Tokens holds the metadata of every personal access token kite has
+	// provisioned on behalf of the user via `kite tokens create`. The
+	// token secrets themselves are never stored, only shown once at
+	// creation time.
+	Tokens []TokenMeta `json:"tokens,omitempty"`
+
+	// InsecureConfigDetected is set by Load when the on-disk config file
+	// still carries a secret that predates the keystore integration.
+	// Calling Save migrates those secrets into the keystore and strips
+	// them from the file.
+	InsecureConfigDetected bool `json:"-"`
+}
+
+// TokenMeta is the locally stored metadata of a PagerDuty personal access
+// token provisioned through `kite tokens`.
+type TokenMeta struct {
+	// ID is the token's PagerDuty-assigned identifier.
+	ID string `json:"id"`
+
+	// Name is the human readable name given at creation time.
+	Name string `json:"name"`
+
+	// Scope records the access level requested at creation time, e.g.
+	// "read" or "write". It is kite-side bookkeeping: the PagerDuty user
+	// token API does not itself scope tokens.
+	Scope string `json:"scope,omitempty"`
+
+	// CreatedAt is when the token was created.
+	CreatedAt time.Time `json:"created_at"`
+
+	// LastUsedAt is the last time PagerDuty observed the token being used.
+	LastUsedAt time.Time `json:"last_used_at,omitempty"`
+
+	// Expiry is when the token stops being valid, if a --lifetime was
+	// given at creation time.
+	Expiry time.Time `json:"expiry,omitempty"`
+}
+
+// getConfigDir returns the absolute path to the directory that stores the
+// kite configuration file, creating it if it does not already exist.
+func getConfigDir() (string, error) {
+	home, err := os.UserHomeDir()
+
+	if err != nil {
+		return "", err
+	}
+
+	dir := filepath.Join(home, constants.ConfigDirName)
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+
+	return dir, nil
+}
+
+// getConfigFile returns the absolute path to the kite configuration file.
+func getConfigFile() (string, error) {
+	dir, err := getConfigDir()
+
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, constants.ConfigFileName), nil
+}
+
+// Load reads the configuration file from disk and returns the Config of the
+// active profile, resolved via ActiveProfile. A config file written before
+// multi-profile support was added is transparently migrated into a single
+// "default" profile.
+func Load() (*Config, error) {
+	cfgFile, err := loadConfigFile()
+
+	if err != nil {
+		return nil, err
+	}
+
+	name := ActiveProfile(cfgFile)
+
+	cfg, ok := cfgFile.Profiles[name]
+
+	if !ok || cfg == nil {
+		return nil, fmt.Errorf("profile %q does not exist", name)
+	}
+
+	cfg.ApiKey.Profile = name
+
+	// A config file written before the keystore integration still carries
+	// its secrets in plaintext; note that so the caller can warn the user,
+	// and fill in anything not already set from the file.
+	cfg.InsecureConfigDetected = hasPlaintextSecrets(cfg)
+
+	if err := fillSecrets(cfg, name); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// Save writes the given Config struct back to the active profile in the
+// kite configuration file, leaving every other profile untouched. ApiKey,
+// AccessToken and the PagerDuty OAuth tokens are pushed to the keystore
+// rather than written to the file; calling Save on a Config loaded from a
+// pre-keystore file therefore also migrates its secrets on the spot.
+func Save(cfg *Config) error {
+	cfgFile, err := loadConfigFile()
+
+	if err != nil {
+		return err
+	}
+
+	name := ActiveProfile(cfgFile)
+
+	if err := saveSecrets(cfg, name); err != nil {
+		return err
+	}
+
+	onDisk := *cfg
+	onDisk.ApiKey.Key = ""
+	onDisk.AccessToken = ""
+	onDisk.OAuthAccessToken = ""
+	onDisk.OAuthRefreshToken = ""
+	onDisk.InsecureConfigDetected = false
+
+	cfgFile.Profiles[name] = &onDisk
+
+	return saveConfigFile(cfgFile)
+}
+
+// configFile is the on-disk layout of the kite configuration file: a named
+// set of profiles plus the profile used when none is selected explicitly.
+type configFile struct {
+	// CurrentProfile is the name of the profile used when neither --profile
+	// nor PDCLI_PROFILE override it. Set via `kite profile use`.
+	CurrentProfile string `json:"current_profile,omitempty"`
+
+	// Profiles holds every profile's configuration, keyed by name.
+	Profiles map[string]*Config `json:"profiles"`
+}
+
+// loadConfigFile reads and parses the kite configuration file, migrating it
+// from the legacy flat schema into a single "default" profile if needed. A
+// missing file is not an error: it yields an empty configFile so that a
+// first-time login can populate it.
+func loadConfigFile() (*configFile, error) {
+	path, err := getConfigFile()
+
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := ioutil.ReadFile(path)
+
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &configFile{Profiles: map[string]*Config{}}, nil
+		}
+
+		return nil, err
+	}
+
+	var probe map[string]json.RawMessage
+
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return nil, err
+	}
+
+	// The multi-profile schema is always keyed by "profiles"; anything else
+	// is the legacy flat schema and needs migrating.
+	if _, ok := probe["profiles"]; !ok {
+		legacy, err := unmarshalLegacyConfig(probe, data)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return &configFile{
+			CurrentProfile: DefaultProfile,
+			Profiles:       map[string]*Config{DefaultProfile: legacy},
+		}, nil
+	}
+
+	cfgFile := new(configFile)
+
+	if err := json.Unmarshal(data, cfgFile); err != nil {
+		return nil, err
+	}
+
+	if cfgFile.Profiles == nil {
+		cfgFile.Profiles = map[string]*Config{}
+	}
+
+	return cfgFile, nil
+}
+
+// unmarshalLegacyConfig unmarshals a legacy, pre-multi-profile config file
+// into a Config. Before the APIKey struct was introduced, "apikey" was a
+// bare string rather than an object; probe is used to detect that shape and
+// migrate it via NewAPIKeyFromString before the rest of data is unmarshaled
+// in the usual way.
+func unmarshalLegacyConfig(probe map[string]json.RawMessage, data []byte) (*Config, error) {
+	legacy := new(Config)
+
+	rawKey, ok := probe["apikey"]
+
+	if !ok {
+		if err := json.Unmarshal(data, legacy); err != nil {
+			return nil, err
+		}
+
+		return legacy, nil
+	}
+
+	var keyString string
+
+	if err := json.Unmarshal(rawKey, &keyString); err != nil {
+		// "apikey" isn't a bare string, so it's already in the current
+		// APIKey shape; unmarshal as usual.
+		if err := json.Unmarshal(data, legacy); err != nil {
+			return nil, err
+		}
+
+		return legacy, nil
+	}
+
+	delete(probe, "apikey")
+
+	rest, err := json.Marshal(probe)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(rest, legacy); err != nil {
+		return nil, err
+	}
+
+	legacy.ApiKey = *NewAPIKeyFromString(keyString)
+
+	return legacy, nil
+}
+
+// saveConfigFile writes the given configFile to disk, overwriting any
+// existing contents.
+func saveConfigFile(cfgFile *configFile) error {
+	path, err := getConfigFile()
+
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(cfgFile, "", "  ")
+
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, data, 0o600)
+}
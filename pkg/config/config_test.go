@@ -0,0 +1,132 @@
+/*
+Copyright © 2021 Red Hat, Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// withHome points os.UserHomeDir at a fresh temp directory for the duration
+// of the test, so loadConfigFile reads/writes in isolation.
+func withHome(t *testing.T) string {
+	t.Helper()
+
+	home := t.TempDir()
+	oldHome := os.Getenv("HOME")
+
+	os.Setenv("HOME", home)
+
+	t.Cleanup(func() {
+		os.Setenv("HOME", oldHome)
+	})
+
+	return home
+}
+
+// writeConfigFile writes contents as the kite config file under home.
+func writeConfigFile(t *testing.T, home, contents string) {
+	t.Helper()
+
+	dir := filepath.Join(home, ".kite")
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(dir, "config.json")
+
+	if err := ioutil.WriteFile(path, []byte(contents), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadConfigFileMigratesLegacyStringAPIKey(t *testing.T) {
+	home := withHome(t)
+
+	writeConfigFile(t, home, `{"apikey":"u+abc123","teamid":"T1","team":"SRE"}`)
+
+	cfgFile, err := loadConfigFile()
+
+	if err != nil {
+		t.Fatalf("loadConfigFile returned an error: %v", err)
+	}
+
+	cfg, ok := cfgFile.Profiles[DefaultProfile]
+
+	if !ok {
+		t.Fatalf("expected profile %q to exist, got %v", DefaultProfile, cfgFile.Profiles)
+	}
+
+	if cfg.ApiKey.Key != "u+abc123" {
+		t.Errorf("ApiKey.Key = %q, want %q", cfg.ApiKey.Key, "u+abc123")
+	}
+
+	if cfg.ApiKey.Scope != ScopeUser {
+		t.Errorf("ApiKey.Scope = %q, want %q", cfg.ApiKey.Scope, ScopeUser)
+	}
+
+	if cfg.TeamID != "T1" || cfg.Team != "SRE" {
+		t.Errorf("TeamID/Team = %q/%q, want %q/%q", cfg.TeamID, cfg.Team, "T1", "SRE")
+	}
+}
+
+func TestLoadConfigFileMigratesLegacyWithoutAPIKey(t *testing.T) {
+	home := withHome(t)
+
+	writeConfigFile(t, home, `{"teamid":"T1"}`)
+
+	cfgFile, err := loadConfigFile()
+
+	if err != nil {
+		t.Fatalf("loadConfigFile returned an error: %v", err)
+	}
+
+	cfg, ok := cfgFile.Profiles[DefaultProfile]
+
+	if !ok {
+		t.Fatalf("expected profile %q to exist, got %v", DefaultProfile, cfgFile.Profiles)
+	}
+
+	if !cfg.ApiKey.IsEmpty() {
+		t.Errorf("expected ApiKey to be empty, got %+v", cfg.ApiKey)
+	}
+}
+
+func TestLoadConfigFileMultiProfileUnaffected(t *testing.T) {
+	home := withHome(t)
+
+	writeConfigFile(t, home, `{"current_profile":"work","profiles":{"work":{"apikey":{"key":"y_xyz","scope":"account"}}}}`)
+
+	cfgFile, err := loadConfigFile()
+
+	if err != nil {
+		t.Fatalf("loadConfigFile returned an error: %v", err)
+	}
+
+	cfg, ok := cfgFile.Profiles["work"]
+
+	if !ok {
+		t.Fatalf("expected profile %q to exist, got %v", "work", cfgFile.Profiles)
+	}
+
+	if cfg.ApiKey.Key != "y_xyz" {
+		t.Errorf("ApiKey.Key = %q, want %q", cfg.ApiKey.Key, "y_xyz")
+	}
+}
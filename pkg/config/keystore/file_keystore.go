@@ -0,0 +1,143 @@
+/*
+Copyright © 2021 Red Hat, Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package keystore
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/openshift/pagerduty-short-circuiter/pkg/constants"
+)
+
+// fileKeystore is the plaintext fallback Keystore, selected by setting
+// PDCLI_KEYSTORE=file. It exists for environments with no OS secret
+// service available, e.g. minimal containers; it offers no protection
+// beyond the file permissions of the underlying file.
+type fileKeystore struct {
+	path string
+}
+
+// newFileKeystore returns a fileKeystore backed by the keystore file in the
+// kite configuration directory.
+func newFileKeystore() *fileKeystore {
+	path, err := keystoreFilePath()
+
+	if err != nil {
+		// The config directory is expected to already be reachable by the
+		// time a Keystore is needed; fall back to the working directory
+		// rather than panicking.
+		path = constants.KeystoreFileName
+	}
+
+	return &fileKeystore{path: path}
+}
+
+// keystoreFilePath returns the absolute path to the plaintext keystore
+// file, creating its parent directory if needed.
+func keystoreFilePath() (string, error) {
+	home, err := os.UserHomeDir()
+
+	if err != nil {
+		return "", err
+	}
+
+	dir := filepath.Join(home, constants.ConfigDirName)
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, constants.KeystoreFileName), nil
+}
+
+// Get implements Keystore.
+func (f *fileKeystore) Get(key string) (string, error) {
+	secrets, err := f.load()
+
+	if err != nil {
+		return "", err
+	}
+
+	value, ok := secrets[key]
+
+	if !ok {
+		return "", ErrNotFound
+	}
+
+	return value, nil
+}
+
+// Set implements Keystore.
+func (f *fileKeystore) Set(key, value string) error {
+	secrets, err := f.load()
+
+	if err != nil {
+		return err
+	}
+
+	secrets[key] = value
+
+	return f.save(secrets)
+}
+
+// Delete implements Keystore.
+func (f *fileKeystore) Delete(key string) error {
+	secrets, err := f.load()
+
+	if err != nil {
+		return err
+	}
+
+	delete(secrets, key)
+
+	return f.save(secrets)
+}
+
+// load reads the plaintext keystore file, returning an empty map if it
+// does not yet exist.
+func (f *fileKeystore) load() (map[string]string, error) {
+	data, err := ioutil.ReadFile(f.path)
+
+	if err != nil {
+		if os.IsNotExist(err) {
+			return map[string]string{}, nil
+		}
+
+		return nil, err
+	}
+
+	secrets := map[string]string{}
+
+	if err := json.Unmarshal(data, &secrets); err != nil {
+		return nil, err
+	}
+
+	return secrets, nil
+}
+
+// save writes secrets to the plaintext keystore file.
+func (f *fileKeystore) save(secrets map[string]string) error {
+	data, err := json.MarshalIndent(secrets, "", "  ")
+
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(f.path, data, 0o600)
+}
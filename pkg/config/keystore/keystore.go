@@ -0,0 +1,58 @@
+/*
+Copyright © 2021 Red Hat, Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package keystore abstracts where kite stores sensitive credentials,
+// so that they never need to be written to the plaintext configuration
+// file. The default backend is the OS-native credential store (macOS
+// Keychain, Windows Credential Manager, or libsecret on Linux, via
+// github.com/zalando/go-keyring); setting PDCLI_KEYSTORE=file selects a
+// plaintext file instead, for environments with no secret service
+// available, e.g. minimal containers.
+package keystore
+
+import (
+	"errors"
+	"os"
+)
+
+// ErrNotFound is returned by Get when no value is stored for key.
+var ErrNotFound = errors.New("keystore: key not found")
+
+// Keystore stores and retrieves secrets by key.
+type Keystore interface {
+	// Get returns the value stored for key, or ErrNotFound if there is none.
+	Get(key string) (string, error)
+
+	// Set stores value under key, overwriting any existing value.
+	Set(key, value string) error
+
+	// Delete removes key, if present. Deleting an absent key is not an
+	// error.
+	Delete(key string) error
+}
+
+// keystoreEnvVar selects a non-default Keystore backend. Currently the
+// only recognized value is "file", which forces the plaintext fallback.
+const keystoreEnvVar = "PDCLI_KEYSTORE"
+
+// New returns the Keystore kite should use, honoring PDCLI_KEYSTORE.
+func New() Keystore {
+	if os.Getenv(keystoreEnvVar) == "file" {
+		return newFileKeystore()
+	}
+
+	return new(osKeystore)
+}
@@ -0,0 +1,59 @@
+/*
+Copyright © 2021 Red Hat, Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package keystore
+
+import (
+	"errors"
+
+	"github.com/zalando/go-keyring"
+)
+
+// service is the name kite's secrets are grouped under in the OS credential
+// store.
+const service = "kite"
+
+// osKeystore stores secrets in the OS-native credential store: macOS
+// Keychain, Windows Credential Manager, or libsecret on Linux. go-keyring
+// selects the right backend for the current platform via build tags.
+type osKeystore struct{}
+
+// Get implements Keystore.
+func (*osKeystore) Get(key string) (string, error) {
+	value, err := keyring.Get(service, key)
+
+	if errors.Is(err, keyring.ErrNotFound) {
+		return "", ErrNotFound
+	}
+
+	return value, err
+}
+
+// Set implements Keystore.
+func (*osKeystore) Set(key, value string) error {
+	return keyring.Set(service, key, value)
+}
+
+// Delete implements Keystore.
+func (*osKeystore) Delete(key string) error {
+	err := keyring.Delete(service, key)
+
+	if errors.Is(err, keyring.ErrNotFound) {
+		return nil
+	}
+
+	return err
+}
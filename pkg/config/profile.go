@@ -0,0 +1,129 @@
+/*
+Copyright © 2021 Red Hat, Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"sort"
+)
+
+// DefaultProfile is the profile name used when a user has never created one
+// of their own, and the one a legacy, pre-multi-profile config is migrated
+// into.
+const DefaultProfile = "default"
+
+// profileEnvVar is the environment variable that overrides which profile is
+// active, taking precedence over the persisted current profile but not over
+// an explicit --profile flag passed via UseProfile.
+const profileEnvVar = "PDCLI_PROFILE"
+
+// profileOverride holds the profile name requested via the --profile flag,
+// set by UseProfile. It takes precedence over every other source.
+var profileOverride string
+
+// UseProfile sets the profile that Load and Save operate against for the
+// remainder of the process, overriding PDCLI_PROFILE and the persisted
+// current profile. It is intended to be called once, from the --profile
+// flag handling in cmd/kite's root command.
+func UseProfile(name string) {
+	profileOverride = name
+}
+
+// ActiveProfile resolves the name of the profile that should be used,
+// preferring, in order: the --profile flag (via UseProfile), the
+// PDCLI_PROFILE environment variable, the persisted current profile, and
+// finally DefaultProfile.
+func ActiveProfile(cfgFile *configFile) string {
+	if profileOverride != "" {
+		return profileOverride
+	}
+
+	if env := os.Getenv(profileEnvVar); env != "" {
+		return env
+	}
+
+	if cfgFile.CurrentProfile != "" {
+		return cfgFile.CurrentProfile
+	}
+
+	return DefaultProfile
+}
+
+// ListProfiles returns the names of every profile on disk, sorted
+// alphabetically.
+func ListProfiles() ([]string, error) {
+	cfgFile, err := loadConfigFile()
+
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(cfgFile.Profiles))
+
+	for name := range cfgFile.Profiles {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names, nil
+}
+
+// SetCurrentProfile persists name as the profile used when neither
+// --profile nor PDCLI_PROFILE are set.
+func SetCurrentProfile(name string) error {
+	cfgFile, err := loadConfigFile()
+
+	if err != nil {
+		return err
+	}
+
+	if _, ok := cfgFile.Profiles[name]; !ok {
+		return fmt.Errorf("profile %q does not exist", name)
+	}
+
+	cfgFile.CurrentProfile = name
+
+	return saveConfigFile(cfgFile)
+}
+
+// DeleteProfile removes the named profile from the kite configuration file,
+// along with its secrets in the keystore.
+func DeleteProfile(name string) error {
+	cfgFile, err := loadConfigFile()
+
+	if err != nil {
+		return err
+	}
+
+	if _, ok := cfgFile.Profiles[name]; !ok {
+		return fmt.Errorf("profile %q does not exist", name)
+	}
+
+	if err := purgeSecrets(name); err != nil {
+		return err
+	}
+
+	delete(cfgFile.Profiles, name)
+
+	if cfgFile.CurrentProfile == name {
+		cfgFile.CurrentProfile = ""
+	}
+
+	return saveConfigFile(cfgFile)
+}
@@ -0,0 +1,172 @@
+/*
+Copyright © 2021 Red Hat, Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/openshift/pagerduty-short-circuiter/pkg/config/keystore"
+)
+
+// secretField names the pieces of Config that are stored in the keystore
+// instead of the plaintext configuration file.
+type secretField string
+
+const (
+	secretAPIKey            secretField = "apikey"
+	secretAccessToken       secretField = "accesstoken"
+	secretOAuthAccessToken  secretField = "oauth_access_token"
+	secretOAuthRefreshToken secretField = "oauth_refresh_token"
+)
+
+// secretKey returns the keystore key a given profile's secret is stored
+// under, e.g. "pdcli:apikey:prod".
+func secretKey(field secretField, profile string) string {
+	return fmt.Sprintf("pdcli:%s:%s", field, profile)
+}
+
+// hasPlaintextSecrets reports whether cfg, as just unmarshaled from the
+// configuration file, already carries a secret directly. This is only true
+// for a file written before the keystore integration existed.
+func hasPlaintextSecrets(cfg *Config) bool {
+	return !cfg.ApiKey.IsEmpty() ||
+		cfg.AccessToken != "" ||
+		cfg.OAuthAccessToken != "" ||
+		cfg.OAuthRefreshToken != ""
+}
+
+// fillSecrets populates any of cfg's secret fields that are still empty
+// from the keystore. Fields already set, e.g. from a pre-keystore
+// plaintext file, are left untouched so Save can migrate them.
+func fillSecrets(cfg *Config, profile string) error {
+	ks := keystore.New()
+
+	if cfg.ApiKey.Key == "" {
+		value, err := getSecret(ks, secretAPIKey, profile)
+
+		if err != nil {
+			return err
+		}
+
+		cfg.ApiKey.Key = value
+	}
+
+	if cfg.AccessToken == "" {
+		value, err := getSecret(ks, secretAccessToken, profile)
+
+		if err != nil {
+			return err
+		}
+
+		cfg.AccessToken = value
+	}
+
+	if cfg.OAuthAccessToken == "" {
+		value, err := getSecret(ks, secretOAuthAccessToken, profile)
+
+		if err != nil {
+			return err
+		}
+
+		cfg.OAuthAccessToken = value
+	}
+
+	if cfg.OAuthRefreshToken == "" {
+		value, err := getSecret(ks, secretOAuthRefreshToken, profile)
+
+		if err != nil {
+			return err
+		}
+
+		cfg.OAuthRefreshToken = value
+	}
+
+	return nil
+}
+
+// saveSecrets writes cfg's secret fields to the keystore, deleting any that
+// are empty.
+func saveSecrets(cfg *Config, profile string) error {
+	ks := keystore.New()
+
+	if err := putSecret(ks, secretAPIKey, profile, cfg.ApiKey.Key); err != nil {
+		return err
+	}
+
+	if err := putSecret(ks, secretAccessToken, profile, cfg.AccessToken); err != nil {
+		return err
+	}
+
+	if err := putSecret(ks, secretOAuthAccessToken, profile, cfg.OAuthAccessToken); err != nil {
+		return err
+	}
+
+	if err := putSecret(ks, secretOAuthRefreshToken, profile, cfg.OAuthRefreshToken); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// purgeSecrets deletes every secret field stored in the keystore for
+// profile, e.g. because its profile is being removed entirely.
+func purgeSecrets(profile string) error {
+	ks := keystore.New()
+
+	if err := putSecret(ks, secretAPIKey, profile, ""); err != nil {
+		return err
+	}
+
+	if err := putSecret(ks, secretAccessToken, profile, ""); err != nil {
+		return err
+	}
+
+	if err := putSecret(ks, secretOAuthAccessToken, profile, ""); err != nil {
+		return err
+	}
+
+	if err := putSecret(ks, secretOAuthRefreshToken, profile, ""); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// getSecret reads field for profile from ks, treating a missing key as an
+// empty value rather than an error.
+func getSecret(ks keystore.Keystore, field secretField, profile string) (string, error) {
+	value, err := ks.Get(secretKey(field, profile))
+
+	if errors.Is(err, keystore.ErrNotFound) {
+		return "", nil
+	}
+
+	return value, err
+}
+
+// putSecret stores value under field for profile, deleting the key instead
+// when value is empty so the keystore doesn't accumulate empty entries.
+func putSecret(ks keystore.Keystore, field secretField, profile, value string) error {
+	key := secretKey(field, profile)
+
+	if value == "" {
+		return ks.Delete(key)
+	}
+
+	return ks.Set(key, value)
+}
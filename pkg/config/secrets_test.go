@@ -0,0 +1,133 @@
+/*
+Copyright © 2021 Red Hat, Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// withFileKeystore points the keystore at the plaintext file backend for
+// the duration of the test, so secrets round-trip without depending on an
+// OS secret service being available in the test environment.
+func withFileKeystore(t *testing.T) {
+	t.Helper()
+
+	old := os.Getenv("PDCLI_KEYSTORE")
+
+	os.Setenv("PDCLI_KEYSTORE", "file")
+
+	t.Cleanup(func() {
+		os.Setenv("PDCLI_KEYSTORE", old)
+	})
+}
+
+func TestSaveStripsSecretsFromDiskAndKeystoreRoundTrips(t *testing.T) {
+	home := withHome(t)
+	withFileKeystore(t)
+
+	cfg := &Config{
+		ApiKey:            *NewAPIKeyFromString("u+secret"),
+		AccessToken:       "gh-secret",
+		OAuthAccessToken:  "oauth-access-secret",
+		OAuthRefreshToken: "oauth-refresh-secret",
+		TeamID:            "T1",
+	}
+
+	if err := Save(cfg); err != nil {
+		t.Fatalf("Save returned an error: %v", err)
+	}
+
+	raw, err := ioutil.ReadFile(filepath.Join(home, ".kite", "config.json"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, secret := range []string{"u+secret", "gh-secret", "oauth-access-secret", "oauth-refresh-secret"} {
+		if strings.Contains(string(raw), secret) {
+			t.Errorf("on-disk config file contains secret %q:\n%s", secret, raw)
+		}
+	}
+
+	loaded, err := Load()
+
+	if err != nil {
+		t.Fatalf("Load returned an error: %v", err)
+	}
+
+	if loaded.ApiKey.Key != "u+secret" {
+		t.Errorf("ApiKey.Key = %q, want %q", loaded.ApiKey.Key, "u+secret")
+	}
+
+	if loaded.AccessToken != "gh-secret" {
+		t.Errorf("AccessToken = %q, want %q", loaded.AccessToken, "gh-secret")
+	}
+
+	if loaded.OAuthAccessToken != "oauth-access-secret" {
+		t.Errorf("OAuthAccessToken = %q, want %q", loaded.OAuthAccessToken, "oauth-access-secret")
+	}
+
+	if loaded.OAuthRefreshToken != "oauth-refresh-secret" {
+		t.Errorf("OAuthRefreshToken = %q, want %q", loaded.OAuthRefreshToken, "oauth-refresh-secret")
+	}
+}
+
+func TestPurgeSecretsDeletesFromKeystore(t *testing.T) {
+	withHome(t)
+	withFileKeystore(t)
+
+	cfg := &Config{
+		ApiKey:            *NewAPIKeyFromString("u+secret"),
+		AccessToken:       "gh-secret",
+		OAuthAccessToken:  "oauth-access-secret",
+		OAuthRefreshToken: "oauth-refresh-secret",
+	}
+
+	if err := Save(cfg); err != nil {
+		t.Fatalf("Save returned an error: %v", err)
+	}
+
+	if err := purgeSecrets(DefaultProfile); err != nil {
+		t.Fatalf("purgeSecrets returned an error: %v", err)
+	}
+
+	loaded, err := Load()
+
+	if err != nil {
+		t.Fatalf("Load returned an error: %v", err)
+	}
+
+	if !loaded.ApiKey.IsEmpty() {
+		t.Errorf("expected ApiKey to be purged, got %+v", loaded.ApiKey)
+	}
+
+	if loaded.AccessToken != "" {
+		t.Errorf("expected AccessToken to be purged, got %q", loaded.AccessToken)
+	}
+
+	if loaded.OAuthAccessToken != "" {
+		t.Errorf("expected OAuthAccessToken to be purged, got %q", loaded.OAuthAccessToken)
+	}
+
+	if loaded.OAuthRefreshToken != "" {
+		t.Errorf("expected OAuthRefreshToken to be purged, got %q", loaded.OAuthRefreshToken)
+	}
+}
@@ -0,0 +1,41 @@
+/*
+Copyright © 2021 Red Hat, Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package constants
+
+const (
+	// APIKeyURL is the URL used to generate a new PagerDuty API key.
+	APIKeyURL = "https://support.pagerduty.com/docs/api-access-keys"
+
+	// AccessTokenURL is the URL used to generate a new GitHub personal access token.
+	AccessTokenURL = "https://github.com/settings/tokens"
+
+	// UserTokenURL is the URL a PagerDuty user visits to manually create a
+	// personal access token, for use when `kite tokens create` cannot
+	// provision one via the API.
+	UserTokenURL = "https://support.pagerduty.com/docs/user-tokens"
+
+	// ConfigFileName is the name of the kite configuration file.
+	ConfigFileName = "config.json"
+
+	// KeystoreFileName is the name of the plaintext fallback keystore file,
+	// used when PDCLI_KEYSTORE=file is set.
+	KeystoreFileName = "keystore.json"
+
+	// ConfigDirName is the name of the directory, relative to the user's home
+	// directory, where the kite configuration file is stored.
+	ConfigDirName = ".kite"
+)
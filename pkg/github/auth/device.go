@@ -0,0 +1,191 @@
+/*
+Copyright © 2021 Red Hat, Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package auth implements GitHub's OAuth 2.0 device flow, used by kite to
+// obtain a GitHub access token without asking the user to paste a personal
+// access token generated through the browser.
+package auth
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// deviceCodeURL is GitHub's endpoint for starting the device flow.
+	deviceCodeURL = "https://github.com/login/device/code"
+
+	// accessTokenURL is GitHub's endpoint for polling for the access token.
+	accessTokenURL = "https://github.com/login/oauth/access_token"
+
+	// slowDownIncrement is how much longer to wait between polls after a
+	// "slow_down" response, per the OAuth device flow spec.
+	slowDownIncrement = 5 * time.Second
+)
+
+// Config holds the client details needed to drive GitHub's device flow.
+type Config struct {
+	ClientID string
+	Scopes   []string
+}
+
+// Token is the outcome of a successful device flow login.
+type Token struct {
+	AccessToken string
+	Scopes      []string
+}
+
+// deviceCodeResponse is GitHub's response to starting the device flow.
+type deviceCodeResponse struct {
+	DeviceCode      string `json:"device_code"`
+	UserCode        string `json:"user_code"`
+	VerificationURI string `json:"verification_uri"`
+	ExpiresIn       int    `json:"expires_in"`
+	Interval        int    `json:"interval"`
+}
+
+// tokenResponse is GitHub's response while polling for the access token.
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	Scope       string `json:"scope"`
+	Error       string `json:"error"`
+}
+
+// Login drives GitHub's OAuth 2.0 device flow end to end: it requests a
+// device code, prints the user code and verification URL for the user to
+// visit, then polls until the user completes the browser step, the flow is
+// denied, or it expires.
+func (c *Config) Login() (*Token, error) {
+	device, err := c.requestDeviceCode()
+
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Printf("First copy your one-time code: %s\n", device.UserCode)
+	fmt.Printf("Then open %s and paste it in.\n", device.VerificationURI)
+
+	return c.poll(device)
+}
+
+// requestDeviceCode asks GitHub for a new device and user code.
+func (c *Config) requestDeviceCode() (*deviceCodeResponse, error) {
+	values := url.Values{
+		"client_id": {c.ClientID},
+		"scope":     {strings.Join(c.Scopes, " ")},
+	}
+
+	resp, err := postForm(deviceCodeURL, values)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	device := new(deviceCodeResponse)
+
+	if err := json.NewDecoder(resp.Body).Decode(device); err != nil {
+		return nil, err
+	}
+
+	return device, nil
+}
+
+// poll repeatedly asks GitHub whether the user has completed the browser
+// step, respecting the interval and slow_down responses, until it succeeds,
+// is denied, or the device code expires.
+func (c *Config) poll(device *deviceCodeResponse) (*Token, error) {
+	interval := time.Duration(device.Interval) * time.Second
+	deadline := time.Now().Add(time.Duration(device.ExpiresIn) * time.Second)
+
+	values := url.Values{
+		"client_id":   {c.ClientID},
+		"device_code": {device.DeviceCode},
+		"grant_type":  {"urn:ietf:params:oauth:grant-type:device_code"},
+	}
+
+	for {
+		time.Sleep(interval)
+
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("github device flow login timed out, please try again")
+		}
+
+		resp, err := postForm(accessTokenURL, values)
+
+		if err != nil {
+			return nil, err
+		}
+
+		token := new(tokenResponse)
+		decodeErr := json.NewDecoder(resp.Body).Decode(token)
+		resp.Body.Close()
+
+		if decodeErr != nil {
+			return nil, decodeErr
+		}
+
+		switch token.Error {
+		case "":
+			return &Token{
+				AccessToken: token.AccessToken,
+				Scopes:      strings.Split(token.Scope, ","),
+			}, nil
+		case "authorization_pending":
+			continue
+		case "slow_down":
+			interval += slowDownIncrement
+			continue
+		case "expired_token":
+			return nil, fmt.Errorf("github device flow login expired, please try again")
+		case "access_denied":
+			return nil, fmt.Errorf("github device flow login was denied")
+		default:
+			return nil, fmt.Errorf("github device flow login failed: %s", token.Error)
+		}
+	}
+}
+
+// postForm submits values to target as a GitHub device/OAuth endpoint,
+// requesting a JSON response.
+func postForm(target string, values url.Values) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodPost, target, strings.NewReader(values.Encode()))
+
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	return http.DefaultClient.Do(req)
+}
+
+// HasScope reports whether scopes contains the given GitHub OAuth scope.
+func HasScope(scopes []string, scope string) bool {
+	for _, s := range scopes {
+		if s == scope {
+			return true
+		}
+	}
+
+	return false
+}
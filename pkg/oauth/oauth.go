@@ -0,0 +1,243 @@
+/*
+Copyright © 2021 Red Hat, Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package oauth implements the OAuth 2.0 authorization-code-with-PKCE flow
+// used by kite to log a user into PagerDuty through their browser.
+package oauth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"runtime"
+	"time"
+)
+
+const (
+	// AuthorizeURL is PagerDuty's OAuth 2.0 authorization endpoint.
+	AuthorizeURL = "https://app.pagerduty.com/oauth/authorize"
+
+	// TokenURL is PagerDuty's OAuth 2.0 token exchange endpoint.
+	TokenURL = "https://app.pagerduty.com/oauth/token"
+
+	// callbackTimeout bounds how long kite waits for the browser redirect
+	// before giving up on the login attempt.
+	callbackTimeout = 5 * time.Minute
+)
+
+// Token is the set of credentials returned by a successful token exchange.
+type Token struct {
+	AccessToken  string    `json:"access_token"`
+	RefreshToken string    `json:"refresh_token"`
+	TokenType    string    `json:"token_type"`
+	ExpiresIn    int       `json:"expires_in"`
+	Expiry       time.Time `json:"-"`
+}
+
+// Config holds the client details needed to drive the PKCE flow.
+type Config struct {
+	ClientID string
+	Scopes   []string
+}
+
+// Login opens the user's browser to PagerDuty's authorization endpoint,
+// waits for the redirect callback on a local loopback server and exchanges
+// the returned authorization code for a Token.
+func (c *Config) Login(ctx context.Context) (*Token, error) {
+	verifier, challenge, err := generatePKCE()
+
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := randomString(32)
+
+	if err != nil {
+		return nil, err
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to start local callback server: %w", err)
+	}
+
+	redirectURI := fmt.Sprintf("http://127.0.0.1:%d/callback", listener.Addr().(*net.TCPAddr).Port)
+
+	authURL := c.buildAuthURL(redirectURI, state, challenge)
+
+	codeCh := make(chan string, 1)
+	errCh := make(chan error, 1)
+
+	server := &http.Server{Handler: callbackHandler(state, codeCh, errCh)}
+
+	go func() {
+		if err := server.Serve(listener); err != http.ErrServerClosed {
+			errCh <- err
+		}
+	}()
+
+	defer server.Close()
+
+	if err := openBrowser(authURL); err != nil {
+		fmt.Printf("Could not open a browser automatically, please visit:\n%s\n", authURL)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, callbackTimeout)
+	defer cancel()
+
+	select {
+	case code := <-codeCh:
+		return c.exchangeCode(code, verifier, redirectURI)
+	case err := <-errCh:
+		return nil, err
+	case <-ctx.Done():
+		return nil, fmt.Errorf("timed out waiting for PagerDuty login callback")
+	}
+}
+
+// Refresh exchanges a refresh token for a new access token.
+func (c *Config) Refresh(refreshToken string) (*Token, error) {
+	values := url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {refreshToken},
+		"client_id":     {c.ClientID},
+	}
+
+	return postToken(values)
+}
+
+// buildAuthURL assembles the full PagerDuty authorization URL for the flow.
+func (c *Config) buildAuthURL(redirectURI, state, challenge string) string {
+	values := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {c.ClientID},
+		"redirect_uri":          {redirectURI},
+		"state":                 {state},
+		"code_challenge":        {challenge},
+		"code_challenge_method": {"S256"},
+		"scope":                 {fmt.Sprintf("%s", joinScopes(c.Scopes))},
+	}
+
+	return AuthorizeURL + "?" + values.Encode()
+}
+
+// exchangeCode trades an authorization code for an access and refresh token.
+func (c *Config) exchangeCode(code, verifier, redirectURI string) (*Token, error) {
+	values := url.Values{
+		"grant_type":    {"authorization_code"},
+		"client_id":     {c.ClientID},
+		"code":          {code},
+		"redirect_uri":  {redirectURI},
+		"code_verifier": {verifier},
+	}
+
+	return postToken(values)
+}
+
+// callbackHandler returns an http.Handler that captures the authorization
+// code (or error) from PagerDuty's redirect and reports it on the given
+// channels, rejecting any request whose state does not match.
+func callbackHandler(state string, codeCh chan<- string, errCh chan<- error) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Anything else hitting the loopback server, e.g. a browser's
+		// automatic favicon fetch, should be ignored rather than treated
+		// as a failed login attempt.
+		if r.URL.Path != "/callback" {
+			http.NotFound(w, r)
+			return
+		}
+
+		query := r.URL.Query()
+
+		if query.Get("state") != state {
+			http.Error(w, "state mismatch", http.StatusBadRequest)
+			errCh <- fmt.Errorf("oauth callback state mismatch")
+			return
+		}
+
+		if errMsg := query.Get("error"); errMsg != "" {
+			http.Error(w, errMsg, http.StatusBadRequest)
+			errCh <- fmt.Errorf("oauth authorization failed: %s", errMsg)
+			return
+		}
+
+		code := query.Get("code")
+
+		fmt.Fprintln(w, "Login successful, you can close this tab and return to the terminal.")
+
+		codeCh <- code
+	}
+}
+
+// generatePKCE returns a random code_verifier and its S256 code_challenge.
+func generatePKCE() (verifier string, challenge string, err error) {
+	verifier, err = randomString(64)
+
+	if err != nil {
+		return "", "", err
+	}
+
+	sum := sha256.Sum256([]byte(verifier))
+	challenge = base64.RawURLEncoding.EncodeToString(sum[:])
+
+	return verifier, challenge, nil
+}
+
+// randomString returns a URL-safe, base64 encoded random string of n bytes
+// of entropy.
+func randomString(n int) (string, error) {
+	buf := make([]byte, n)
+
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// joinScopes joins OAuth scopes with a space, as required by the spec.
+func joinScopes(scopes []string) string {
+	joined := ""
+
+	for i, scope := range scopes {
+		if i > 0 {
+			joined += " "
+		}
+
+		joined += scope
+	}
+
+	return joined
+}
+
+// openBrowser opens the given URL in the user's default browser.
+func openBrowser(target string) error {
+	switch runtime.GOOS {
+	case "darwin":
+		return exec.Command("open", target).Start()
+	case "windows":
+		return exec.Command("rundll32", "url.dll,FileProtocolHandler", target).Start()
+	default:
+		return exec.Command("xdg-open", target).Start()
+	}
+}